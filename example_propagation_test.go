@@ -0,0 +1,36 @@
+package twirpotel_test
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/twitchtv/twirp"
+	"github.com/twitchtv/twirp/example"
+
+	"github.com/bakins/twirpotel"
+)
+
+// ExampleMiddleware wires up a twirp server and client so that the client
+// span and the server span are connected as parent and child rather than
+// living in separate traces. The client interceptor injects the trace
+// context headers and twirpotel.Middleware extracts them on the server
+// before the request reaches the twirp handler. Both use
+// otel.GetTextMapPropagator() here, so a propagator such as
+// propagation.TraceContext{} (W3C traceparent/tracestate) must have been set
+// with otel.SetTextMapPropagator for any headers to be sent.
+func ExampleMiddleware() {
+	server := example.NewHaberdasherServer(
+		&randomHaberdasher{},
+		twirp.WithServerInterceptors(twirpotel.ServerInterceptor()),
+	)
+
+	http.Handle(server.PathPrefix(), twirpotel.Middleware(server))
+
+	client := example.NewHaberdasherProtobufClient(
+		"http://localhost:8080",
+		http.DefaultClient,
+		twirp.WithClientInterceptors(twirpotel.ClientInterceptor()),
+	)
+
+	_, _ = client.MakeHat(context.Background(), &example.Size{Inches: 10})
+}
@@ -0,0 +1,42 @@
+package twirpotel
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// SpanNameFormatter formats the name of the span created for an RPC, given
+// its twirp package, service and method names.
+type SpanNameFormatter func(ctx context.Context, pkg, service, method string) string
+
+// SpanFilter reports whether a span should be created for an RPC, given its
+// twirp package, service and method names. Returning false skips span
+// creation entirely, which is useful for excluding noisy RPCs, such as
+// health checks, without relying on exporter-side sampling.
+type SpanFilter func(ctx context.Context, pkg, service, method string) bool
+
+// WithSpanNameFormatter returns an Option that overrides the default
+// "pkg.service/method" span name.
+func WithSpanNameFormatter(formatter SpanNameFormatter) Option {
+	return optionFunc(func(c *config) {
+		c.spanNameFormatter = formatter
+	})
+}
+
+// WithSpanFilter returns an Option that skips span creation for RPCs for
+// which filter returns false.
+func WithSpanFilter(filter SpanFilter) Option {
+	return optionFunc(func(c *config) {
+		c.spanFilter = filter
+	})
+}
+
+// WithAttributes returns an Option that adds static attributes to every
+// span created by the interceptor, such as a deployment environment or
+// service instance ID.
+func WithAttributes(attributes ...attribute.KeyValue) Option {
+	return optionFunc(func(c *config) {
+		c.staticAttributes = append(c.staticAttributes, attributes...)
+	})
+}
@@ -0,0 +1,122 @@
+package twirpotel_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/twitchtv/twirp"
+	"github.com/twitchtv/twirp/example"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/bakins/twirpotel"
+)
+
+func TestSpanNameFormatter(t *testing.T) {
+	var exp exporter
+
+	provider := trace.NewTracerProvider(
+		trace.WithSyncer(&exp),
+	)
+
+	old := otel.GetTracerProvider()
+
+	otel.SetTracerProvider(provider)
+
+	defer func() {
+		otel.SetTracerProvider(old)
+	}()
+
+	formatter := func(_ context.Context, pkg, service, method string) string {
+		return service + "." + method
+	}
+
+	svr := httptest.NewServer(
+		example.NewHaberdasherServer(
+			&randomHaberdasher{},
+			twirp.WithServerInterceptors(twirpotel.ServerInterceptor(twirpotel.WithSpanNameFormatter(formatter))),
+		),
+	)
+	defer svr.Close()
+
+	client := example.NewHaberdasherProtobufClient(svr.URL, http.DefaultClient)
+
+	_, err := client.MakeHat(context.Background(), &example.Size{Inches: 10})
+	require.NoError(t, err)
+
+	require.Len(t, exp.spans, 1)
+	require.Equal(t, "Haberdasher.MakeHat", exp.spans[0].Name())
+}
+
+func TestSpanFilter(t *testing.T) {
+	var exp exporter
+
+	provider := trace.NewTracerProvider(
+		trace.WithSyncer(&exp),
+	)
+
+	old := otel.GetTracerProvider()
+
+	otel.SetTracerProvider(provider)
+
+	defer func() {
+		otel.SetTracerProvider(old)
+	}()
+
+	filter := func(_ context.Context, _, _, method string) bool {
+		return method != "MakeHat"
+	}
+
+	svr := httptest.NewServer(
+		example.NewHaberdasherServer(
+			&randomHaberdasher{},
+			twirp.WithServerInterceptors(twirpotel.ServerInterceptor(twirpotel.WithSpanFilter(filter))),
+		),
+	)
+	defer svr.Close()
+
+	client := example.NewHaberdasherProtobufClient(svr.URL, http.DefaultClient)
+
+	_, err := client.MakeHat(context.Background(), &example.Size{Inches: 10})
+	require.NoError(t, err)
+
+	require.Empty(t, exp.spans)
+}
+
+func TestWithAttributes(t *testing.T) {
+	var exp exporter
+
+	provider := trace.NewTracerProvider(
+		trace.WithSyncer(&exp),
+	)
+
+	old := otel.GetTracerProvider()
+
+	otel.SetTracerProvider(provider)
+
+	defer func() {
+		otel.SetTracerProvider(old)
+	}()
+
+	svr := httptest.NewServer(
+		example.NewHaberdasherServer(
+			&randomHaberdasher{},
+			twirp.WithServerInterceptors(twirpotel.ServerInterceptor(
+				twirpotel.WithAttributes(attribute.String("deployment.environment", "test")),
+			)),
+		),
+	)
+	defer svr.Close()
+
+	client := example.NewHaberdasherProtobufClient(svr.URL, http.DefaultClient)
+
+	_, err := client.MakeHat(context.Background(), &example.Size{Inches: 10})
+	require.NoError(t, err)
+
+	require.Len(t, exp.spans, 1)
+	requireAttribute(t, exp.spans[0].Attributes(), attribute.Key("deployment.environment"), "test")
+}
@@ -0,0 +1,122 @@
+package twirpotel
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/twitchtv/twirp"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	requestHeaderAttributePrefix  = "http.request.header."
+	responseHeaderAttributePrefix = "http.response.header."
+)
+
+// WithCapturedRequestHeaders returns an Option that records the named HTTP
+// request headers as span attributes, using the
+// "http.request.header.<name>" naming convention. Header names are matched
+// case-insensitively.
+func WithCapturedRequestHeaders(headers []string) Option {
+	return optionFunc(func(c *config) {
+		c.capturedRequestHeaders = headers
+	})
+}
+
+// WithCapturedResponseHeaders returns an Option that records the named HTTP
+// response headers as span attributes, using the
+// "http.response.header.<name>" naming convention. Header names are matched
+// case-insensitively.
+func WithCapturedResponseHeaders(headers []string) Option {
+	return optionFunc(func(c *config) {
+		c.capturedResponseHeaders = headers
+	})
+}
+
+// normalizeHeaderName lowercases a header name and replaces "-" with "_", to
+// match OpenTelemetry semantic conventions for HTTP header attribute keys.
+func normalizeHeaderName(name string) string {
+	return strings.ReplaceAll(strings.ToLower(name), "-", "_")
+}
+
+func headerAttributes(prefix string, names []string, header http.Header) []attribute.KeyValue {
+	if len(names) == 0 || header == nil {
+		return nil
+	}
+
+	var attributes []attribute.KeyValue
+
+	for _, name := range names {
+		values := header.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+
+		key := attribute.Key(prefix + normalizeHeaderName(name))
+
+		attributes = append(attributes, key.StringSlice(values))
+	}
+
+	return attributes
+}
+
+// capturedHeadersContextKey is the unexported context key the interceptors
+// use to retrieve captured headers. On the server, Middleware stashes them,
+// since it has access to http.Header where the interceptors do not. On the
+// client, the interceptor stashes them itself and Transport fills in the
+// response headers.
+type capturedHeadersContextKey struct{}
+
+// capturedHeaders carries header attributes from the HTTP layer to the
+// interceptor that finishes the span.
+type capturedHeaders struct {
+	// requestAttributes is populated up-front, since request headers are
+	// known before the RPC method runs.
+	requestAttributes []attribute.KeyValue
+
+	// responseHeader and responseHeaderNames are read lazily, once the
+	// RPC method has returned and the response headers have been set.
+	responseHeader      http.Header
+	responseHeaderNames []string
+}
+
+func (c *capturedHeaders) attributes() []attribute.KeyValue {
+	if c == nil {
+		return nil
+	}
+
+	attributes := append([]attribute.KeyValue{}, c.requestAttributes...)
+
+	return append(attributes, headerAttributes(responseHeaderAttributePrefix, c.responseHeaderNames, c.responseHeader)...)
+}
+
+func contextWithCapturedHeaders(ctx context.Context, c *capturedHeaders) context.Context {
+	return context.WithValue(ctx, capturedHeadersContextKey{}, c)
+}
+
+func capturedHeadersFromContext(ctx context.Context) *capturedHeaders {
+	c, _ := ctx.Value(capturedHeadersContextKey{}).(*capturedHeaders)
+	return c
+}
+
+// prepareClientHeaderCapture stashes a capturedHeaders in ctx for a client
+// RPC, if any headers were configured to be captured. Request header
+// attributes are computed immediately, from the headers the caller has set
+// via twirp.WithHTTPRequestHeaders. Response headers are only observable at
+// the HTTP transport layer, so they are filled in later by Transport, if the
+// caller has wired one into their http.Client.
+func prepareClientHeaderCapture(ctx context.Context, c config) context.Context {
+	if len(c.capturedRequestHeaders) == 0 && len(c.capturedResponseHeaders) == 0 {
+		return ctx
+	}
+
+	header, _ := twirp.HTTPRequestHeaders(ctx)
+
+	capture := &capturedHeaders{
+		requestAttributes:   headerAttributes(requestHeaderAttributePrefix, c.capturedRequestHeaders, header),
+		responseHeaderNames: c.capturedResponseHeaders,
+	}
+
+	return contextWithCapturedHeaders(ctx, capture)
+}
@@ -0,0 +1,108 @@
+package twirpotel_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/twitchtv/twirp"
+	"github.com/twitchtv/twirp/example"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+	spanTrace "go.opentelemetry.io/otel/trace"
+
+	"github.com/bakins/twirpotel"
+)
+
+func TestCapturedHeaders(t *testing.T) {
+	var exp exporter
+
+	provider := trace.NewTracerProvider(
+		trace.WithSyncer(&exp),
+	)
+
+	old := otel.GetTracerProvider()
+
+	otel.SetTracerProvider(provider)
+
+	defer func() {
+		otel.SetTracerProvider(old)
+	}()
+
+	serverOptions := []twirpotel.Option{
+		twirpotel.WithCapturedRequestHeaders([]string{"X-Request-Id"}),
+		twirpotel.WithCapturedResponseHeaders([]string{"X-Hat-Color"}),
+	}
+
+	handler := example.NewHaberdasherServer(
+		&headerSettingHaberdasher{},
+		twirp.WithServerInterceptors(twirpotel.ServerInterceptor(serverOptions...)),
+	)
+
+	svr := httptest.NewServer(twirpotel.Middleware(handler, serverOptions...))
+	defer svr.Close()
+
+	transport := twirpotel.NewTransport(http.DefaultTransport)
+
+	httpClient := &http.Client{Transport: transport}
+
+	clientOptions := []twirpotel.Option{
+		twirpotel.WithCapturedRequestHeaders([]string{"X-Request-Id"}),
+		twirpotel.WithCapturedResponseHeaders([]string{"X-Hat-Color"}),
+	}
+
+	client := example.NewHaberdasherProtobufClient(
+		svr.URL,
+		httpClient,
+		twirp.WithClientInterceptors(twirpotel.ClientInterceptor(clientOptions...)),
+	)
+
+	ctx, err := twirp.WithHTTPRequestHeaders(context.Background(), http.Header{
+		"X-Request-Id": []string{"abc-123"},
+	})
+	require.NoError(t, err)
+
+	resp, err := client.MakeHat(ctx, &example.Size{Inches: 10})
+	require.NoError(t, err)
+	require.NotEmpty(t, resp)
+
+	require.Len(t, exp.spans, 2)
+
+	for _, span := range exp.spans {
+		requireHeaderAttribute(t, span.Attributes(), "http.request.header.x_request_id", "abc-123")
+		requireHeaderAttribute(t, span.Attributes(), "http.response.header.x_hat_color", "red")
+
+		switch span.SpanKind() {
+		case spanTrace.SpanKindClient, spanTrace.SpanKindServer:
+		default:
+			t.Errorf("unexpected span kind %v", span.SpanKind())
+		}
+	}
+}
+
+func requireHeaderAttribute(t *testing.T, attributes []attribute.KeyValue, key, value string) {
+	t.Helper()
+
+	for _, a := range attributes {
+		if string(a.Key) != key {
+			continue
+		}
+
+		require.Equal(t, []string{value}, a.Value.AsStringSlice())
+
+		return
+	}
+
+	t.Errorf("did not find attribute %s", key)
+}
+
+type headerSettingHaberdasher struct{}
+
+func (h *headerSettingHaberdasher) MakeHat(ctx context.Context, size *example.Size) (*example.Hat, error) {
+	_ = twirp.SetHTTPResponseHeader(ctx, "X-Hat-Color", "red")
+
+	return &example.Hat{Size: size.Inches, Color: "red", Name: "top hat"}, nil
+}
@@ -0,0 +1,116 @@
+package twirpotel
+
+import (
+	"context"
+	"time"
+
+	"github.com/twitchtv/twirp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// WithMeterProvider returns an Option that sets the metric.MeterProvider
+// used to create instruments.
+//
+// Default is to use otel.GetMeterProvider().
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return optionFunc(func(c *config) {
+		c.meterProvider = provider
+	})
+}
+
+func (c config) getMeterProvider() metric.MeterProvider {
+	if c.meterProvider != nil {
+		return c.meterProvider
+	}
+
+	return otel.GetMeterProvider()
+}
+
+// ServerMetricsInterceptor creates a twirp.Interceptor that records
+// OpenTelemetry metrics for RPCs handled by a twirp server. It is intended
+// to be used alongside, not instead of, ServerInterceptor.
+func ServerMetricsInterceptor(options ...Option) twirp.Interceptor {
+	return metricsInterceptor("server", options)
+}
+
+// ClientMetricsInterceptor creates a twirp.Interceptor that records
+// OpenTelemetry metrics for RPCs made by a twirp client. It is intended to
+// be used alongside, not instead of, ClientInterceptor.
+func ClientMetricsInterceptor(options ...Option) twirp.Interceptor {
+	return metricsInterceptor("client", options)
+}
+
+func metricsInterceptor(kind string, options []Option) twirp.Interceptor {
+	var c config
+
+	for _, o := range options {
+		o.apply(&c)
+	}
+
+	meter := c.getMeterProvider().Meter(InstrumentationName)
+
+	duration, err := meter.Float64Histogram(
+		"rpc."+kind+".duration",
+		metric.WithUnit("ms"),
+		metric.WithDescription("Duration of twirp RPCs, in milliseconds."),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	requests, err := meter.Int64Counter(
+		"rpc."+kind+".requests",
+		metric.WithDescription("Number of twirp RPCs."),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	inFlight, err := meter.Int64UpDownCounter(
+		"rpc."+kind+".requests.in_flight",
+		metric.WithDescription("Number of in-flight twirp RPCs."),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	return func(next twirp.Method) twirp.Method {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			attributes := rpcAttributes(ctx)
+
+			measurementOption := metric.WithAttributes(attributes...)
+
+			inFlight.Add(ctx, 1, measurementOption)
+			defer inFlight.Add(ctx, -1, measurementOption)
+
+			start := time.Now()
+
+			resp, err := next(ctx, req)
+
+			elapsed := time.Since(start)
+
+			attributes = append(attributes, getTwirpErrorAttributes(err)[0])
+
+			duration.Record(ctx, float64(elapsed.Milliseconds()), metric.WithAttributes(attributes...))
+			requests.Add(ctx, 1, metric.WithAttributes(attributes...))
+
+			return resp, err
+		}
+	}
+}
+
+// rpcAttributes returns the twirp package/service/method attributes for the
+// RPC being handled in ctx, for use as metric attributes.
+func rpcAttributes(ctx context.Context) []attribute.KeyValue {
+	packageName, _ := twirp.PackageName(ctx)
+	serviceName, _ := twirp.ServiceName(ctx)
+	methodName, _ := twirp.MethodName(ctx)
+
+	return []attribute.KeyValue{
+		PackageNameKey.String(packageName),
+		ServiceNameKey.String(serviceName),
+		MethodNameKey.String(methodName),
+	}
+}
@@ -0,0 +1,120 @@
+package twirpotel_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/twitchtv/twirp"
+	"github.com/twitchtv/twirp/example"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/bakins/twirpotel"
+)
+
+func ExampleServerMetricsInterceptor() {
+	// Add the server metrics interceptor alongside ServerInterceptor.
+	ts := example.NewHaberdasherServer(
+		&randomHaberdasher{},
+		twirp.WithServerInterceptors(
+			twirpotel.ServerInterceptor(),
+			twirpotel.ServerMetricsInterceptor(),
+		),
+	)
+
+	http.Handle(ts.PathPrefix(), ts)
+}
+
+func TestMetricsInterceptors(t *testing.T) {
+	tests := map[string]struct {
+		errorCode    twirp.ErrorCode
+		expectedCode string
+	}{
+		"ok": {
+			errorCode:    twirp.NoError,
+			expectedCode: twirpotel.NoErrorCode.AsString(),
+		},
+		"invalid argument": {
+			errorCode:    twirp.InvalidArgument,
+			expectedCode: string(twirp.InvalidArgument),
+		},
+		"non-twirp error": {
+			errorCode:    "testing",
+			expectedCode: string(twirp.Internal),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			reader := sdkmetric.NewManualReader()
+
+			provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+			serverOption := twirpotel.WithMeterProvider(provider)
+			clientOption := twirpotel.WithMeterProvider(provider)
+
+			svr := httptest.NewServer(
+				example.NewHaberdasherServer(
+					&server{errorCode: test.errorCode},
+					twirp.WithServerInterceptors(twirpotel.ServerMetricsInterceptor(serverOption)),
+				),
+			)
+			defer svr.Close()
+
+			client := example.NewHaberdasherProtobufClient(
+				svr.URL,
+				http.DefaultClient,
+				twirp.WithClientInterceptors(twirpotel.ClientMetricsInterceptor(clientOption)),
+			)
+
+			_, err := client.MakeHat(context.Background(), &example.Size{})
+			if test.errorCode == twirp.NoError {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+
+			var data metricdata.ResourceMetrics
+
+			require.NoError(t, reader.Collect(context.Background(), &data))
+
+			require.Len(t, data.ScopeMetrics, 1)
+
+			metricsByName := map[string]metricdata.Metrics{}
+			for _, m := range data.ScopeMetrics[0].Metrics {
+				metricsByName[m.Name] = m
+			}
+
+			duration, ok := metricsByName["rpc.server.duration"].Data.(metricdata.Histogram[float64])
+			require.True(t, ok, "missing rpc.server.duration histogram")
+			require.Len(t, duration.DataPoints, 1)
+			requireMetricAttribute(t, duration.DataPoints[0].Attributes, "twirp.error_code", test.expectedCode)
+
+			requests, ok := metricsByName["rpc.server.requests"].Data.(metricdata.Sum[int64])
+			require.True(t, ok, "missing rpc.server.requests counter")
+			require.Len(t, requests.DataPoints, 1)
+			require.Equal(t, int64(1), requests.DataPoints[0].Value)
+			requireMetricAttribute(t, requests.DataPoints[0].Attributes, "twirp.error_code", test.expectedCode)
+
+			inFlight, ok := metricsByName["rpc.server.requests.in_flight"].Data.(metricdata.Sum[int64])
+			require.True(t, ok, "missing rpc.server.requests.in_flight up/down counter")
+			require.Len(t, inFlight.DataPoints, 1)
+			require.Equal(t, int64(0), inFlight.DataPoints[0].Value)
+
+			_, ok = metricsByName["rpc.client.duration"].Data.(metricdata.Histogram[float64])
+			require.True(t, ok, "missing rpc.client.duration histogram")
+		})
+	}
+}
+
+func requireMetricAttribute(t *testing.T, set attribute.Set, key, value string) {
+	t.Helper()
+
+	got, ok := set.Value(attribute.Key(key))
+	require.True(t, ok, "missing attribute %s", key)
+	require.Equal(t, value, got.AsString())
+}
@@ -0,0 +1,128 @@
+package twirpotel
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/twitchtv/twirp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// WithPropagator returns an Option that sets the propagation.TextMapPropagator
+// used to inject and extract trace context across the wire.
+//
+// Default is to use otel.GetTextMapPropagator().
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return optionFunc(func(c *config) {
+		c.propagator = propagator
+	})
+}
+
+func (c config) getPropagator() propagation.TextMapPropagator {
+	if c.propagator != nil {
+		return c.propagator
+	}
+
+	return otel.GetTextMapPropagator()
+}
+
+// Middleware returns an http.Handler that wraps next and extracts the trace
+// context from incoming request headers, using the propagator set by
+// WithPropagator or otel.GetTextMapPropagator(), before the request reaches
+// the twirp server. With a W3C propagator such as propagation.TraceContext{}
+// this reads traceparent/tracestate. This lets the span created by
+// ServerInterceptor become a child of the calling client's span rather than
+// the root of a new trace.
+//
+// It also gives ServerInterceptor access to the HTTP request and response
+// headers, which Twirp interceptors cannot otherwise see. The headers
+// recorded on the server span are the ones named by the
+// WithCapturedRequestHeaders and WithCapturedResponseHeaders options passed
+// to Middleware; on the server side, those options have no effect when they
+// are passed only to ServerInterceptor.
+//
+// Install it in front of the twirp server handler:
+//
+//	server := example.NewHaberdasherServer(svc, twirp.WithServerInterceptors(twirpotel.ServerInterceptor()))
+//	http.Handle(server.PathPrefix(), twirpotel.Middleware(server))
+func Middleware(next http.Handler, options ...Option) http.Handler {
+	var c config
+
+	for _, o := range options {
+		o.apply(&c)
+	}
+
+	propagator := c.getPropagator()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+		if len(c.capturedRequestHeaders) > 0 || len(c.capturedResponseHeaders) > 0 {
+			ctx = contextWithCapturedHeaders(ctx, &capturedHeaders{
+				requestAttributes:   headerAttributes(requestHeaderAttributePrefix, c.capturedRequestHeaders, r.Header),
+				responseHeader:      w.Header(),
+				responseHeaderNames: c.capturedResponseHeaders,
+			})
+		}
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Transport wraps an http.RoundTripper and injects the current trace context
+// into outgoing request headers. It is an alternative to the automatic
+// propagation done by ClientInterceptor, useful when a caller would rather
+// wire propagation into the HTTP transport used by the generated twirp
+// client.
+type Transport struct {
+	base http.RoundTripper
+	c    config
+}
+
+// NewTransport returns a Transport that injects trace context into requests
+// before delegating to base. If base is nil, http.DefaultTransport is used.
+func NewTransport(base http.RoundTripper, options ...Option) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	var c config
+
+	for _, o := range options {
+		o.apply(&c)
+	}
+
+	return &Transport{
+		base: base,
+		c:    c,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.c.getPropagator().Inject(req.Context(), propagation.HeaderCarrier(req.Header))
+
+	resp, err := t.base.RoundTrip(req)
+	if err == nil {
+		if capture := capturedHeadersFromContext(req.Context()); capture != nil {
+			capture.responseHeader = resp.Header
+		}
+	}
+
+	return resp, err
+}
+
+// injectPropagationHeaders writes the current trace context into the
+// outgoing HTTP request headers of a twirp client call, preserving any
+// headers already set on the context via twirp.WithHTTPRequestHeaders.
+func injectPropagationHeaders(ctx context.Context, c config) (context.Context, error) {
+	header, _ := twirp.HTTPRequestHeaders(ctx)
+	if header == nil {
+		header = make(http.Header)
+	}
+
+	c.getPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+
+	return twirp.WithHTTPRequestHeaders(ctx, header)
+}
@@ -0,0 +1,108 @@
+package twirpotel_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/twitchtv/twirp"
+	"github.com/twitchtv/twirp/example"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace"
+	spanTrace "go.opentelemetry.io/otel/trace"
+
+	"github.com/bakins/twirpotel"
+)
+
+func TestPropagation(t *testing.T) {
+	var exp exporter
+
+	provider := trace.NewTracerProvider(
+		trace.WithSyncer(&exp),
+	)
+
+	old := otel.GetTracerProvider()
+	oldPropagator := otel.GetTextMapPropagator()
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	defer func() {
+		otel.SetTracerProvider(old)
+		otel.SetTextMapPropagator(oldPropagator)
+	}()
+
+	handler := example.NewHaberdasherServer(
+		&randomHaberdasher{},
+		twirp.WithServerInterceptors(twirpotel.ServerInterceptor()),
+	)
+
+	svr := httptest.NewServer(twirpotel.Middleware(handler))
+	defer svr.Close()
+
+	client := example.NewHaberdasherProtobufClient(
+		svr.URL,
+		http.DefaultClient,
+		twirp.WithClientInterceptors(twirpotel.ClientInterceptor()),
+	)
+
+	resp, err := client.MakeHat(context.Background(), &example.Size{Inches: 10})
+	require.NoError(t, err)
+	require.NotEmpty(t, resp)
+
+	require.Len(t, exp.spans, 2)
+
+	var clientSpan, serverSpan trace.ReadOnlySpan
+
+	for _, span := range exp.spans {
+		switch span.SpanKind() {
+		case spanTrace.SpanKindClient:
+			clientSpan = span
+		case spanTrace.SpanKindServer:
+			serverSpan = span
+		}
+	}
+
+	require.NotNil(t, clientSpan)
+	require.NotNil(t, serverSpan)
+
+	require.Equal(t, clientSpan.SpanContext().TraceID(), serverSpan.SpanContext().TraceID())
+	require.Equal(t, clientSpan.SpanContext().SpanID(), serverSpan.Parent().SpanID())
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestTransport(t *testing.T) {
+	oldPropagator := otel.GetTextMapPropagator()
+
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	defer otel.SetTextMapPropagator(oldPropagator)
+
+	var gotHeader http.Header
+
+	base := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		gotHeader = r.Header
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+
+	transport := twirpotel.NewTransport(base)
+
+	ctx, span := otel.Tracer("test").Start(context.Background(), "span")
+	defer span.End()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com", nil)
+	require.NoError(t, err)
+
+	_, err = transport.RoundTrip(req)
+	require.NoError(t, err)
+
+	require.NotEmpty(t, gotHeader.Get("traceparent"))
+}
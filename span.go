@@ -0,0 +1,36 @@
+package twirpotel
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// StartSpan starts an internal-kind child span named name under the span
+// in ctx, using the same tracer-provider resolution logic as the
+// interceptors. It is meant for instrumenting sub-operations, such as
+// database or downstream calls, performed inside a twirp handler.
+func StartSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	var c config
+
+	tracer := c.getTracerProvider(ctx).Tracer(InstrumentationName)
+
+	opts = append([]trace.SpanStartOption{trace.WithSpanKind(trace.SpanKindInternal)}, opts...)
+
+	return tracer.Start(ctx, name, opts...)
+}
+
+// RecordError annotates the span in ctx with the same twirp error
+// attributes and status that ServerInterceptor and ClientInterceptor set on
+// the outer RPC span, so that errors from sub-operations started with
+// StartSpan are reported consistently.
+func RecordError(ctx context.Context, err error) {
+	span := trace.SpanFromContext(ctx)
+
+	span.SetAttributes(getTwirpErrorAttributes(err)...)
+
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
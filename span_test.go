@@ -0,0 +1,131 @@
+package twirpotel_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/twitchtv/twirp"
+	"github.com/twitchtv/twirp/example"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/bakins/twirpotel"
+)
+
+// ExampleStartSpan shows a handler creating local child spans for a
+// database call and a downstream call, annotating either with RecordError
+// if it fails.
+func ExampleStartSpan() {
+	_ = func(ctx context.Context, size *example.Size) (*example.Hat, error) {
+		dbCtx, dbSpan := twirpotel.StartSpan(ctx, "query database")
+		err := queryDatabase(dbCtx)
+		if err != nil {
+			twirpotel.RecordError(dbCtx, err)
+		}
+		dbSpan.End()
+
+		downstreamCtx, downstreamSpan := twirpotel.StartSpan(ctx, "call downstream service")
+		hat, err := callDownstream(downstreamCtx)
+		if err != nil {
+			twirpotel.RecordError(downstreamCtx, err)
+		}
+		downstreamSpan.End()
+
+		return hat, err
+	}
+}
+
+func queryDatabase(context.Context) error { return nil }
+
+func callDownstream(context.Context) (*example.Hat, error) { return &example.Hat{}, nil }
+
+type spanHandler struct {
+	fail bool
+}
+
+func (h *spanHandler) MakeHat(ctx context.Context, size *example.Size) (*example.Hat, error) {
+	childCtx, span := twirpotel.StartSpan(ctx, "make-hat-work")
+	defer span.End()
+
+	if h.fail {
+		err := errors.New("boom")
+		twirpotel.RecordError(childCtx, err)
+
+		return nil, twirp.InternalErrorWith(err)
+	}
+
+	return &example.Hat{}, nil
+}
+
+func TestStartSpanAndRecordError(t *testing.T) {
+	tests := map[string]struct {
+		fail bool
+	}{
+		"ok":    {fail: false},
+		"error": {fail: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var exp exporter
+
+			provider := trace.NewTracerProvider(
+				trace.WithSyncer(&exp),
+			)
+
+			old := otel.GetTracerProvider()
+
+			otel.SetTracerProvider(provider)
+
+			defer func() {
+				otel.SetTracerProvider(old)
+			}()
+
+			svr := httptest.NewServer(
+				example.NewHaberdasherServer(
+					&spanHandler{fail: test.fail},
+					twirp.WithServerInterceptors(twirpotel.ServerInterceptor()),
+				),
+			)
+			defer svr.Close()
+
+			client := example.NewHaberdasherProtobufClient(svr.URL, http.DefaultClient)
+
+			_, err := client.MakeHat(context.Background(), &example.Size{Inches: 10})
+			if test.fail {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Len(t, exp.spans, 2)
+
+			var rpcSpan, childSpan trace.ReadOnlySpan
+
+			for _, span := range exp.spans {
+				if span.Name() == "make-hat-work" {
+					childSpan = span
+					continue
+				}
+
+				rpcSpan = span
+			}
+
+			require.NotNil(t, rpcSpan)
+			require.NotNil(t, childSpan)
+
+			require.Equal(t, rpcSpan.SpanContext().SpanID(), childSpan.Parent().SpanID())
+			require.Equal(t, rpcSpan.SpanContext().TraceID(), childSpan.SpanContext().TraceID())
+
+			if test.fail {
+				requireAttribute(t, childSpan.Attributes(), twirpotel.ErrorCodeKey, string(twirp.Internal))
+				require.Equal(t, codes.Error, childSpan.Status().Code)
+			}
+		})
+	}
+}
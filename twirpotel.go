@@ -8,6 +8,8 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
 	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -37,7 +39,16 @@ const (
 var NoErrorCode = attribute.StringValue("ok")
 
 type config struct {
-	provider trace.TracerProvider
+	provider      trace.TracerProvider
+	propagator    propagation.TextMapPropagator
+	meterProvider metric.MeterProvider
+
+	capturedRequestHeaders  []string
+	capturedResponseHeaders []string
+
+	spanNameFormatter SpanNameFormatter
+	spanFilter        SpanFilter
+	staticAttributes  []attribute.KeyValue
 }
 
 func (c config) getTracerProvider(ctx context.Context) trace.TracerProvider {
@@ -45,7 +56,7 @@ func (c config) getTracerProvider(ctx context.Context) trace.TracerProvider {
 		return c.provider
 	}
 
-	if span := trace.SpanFromContext(ctx); span.SpanContext().IsValid() {
+	if span := trace.SpanFromContext(ctx); span.IsRecording() {
 		return span.TracerProvider()
 	}
 
@@ -92,9 +103,25 @@ func interceptor(kind trace.SpanKind, options []Option) twirp.Interceptor {
 
 	return func(next twirp.Method) twirp.Method {
 		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			packageName, _ := twirp.PackageName(ctx)
+			serviceName, _ := twirp.ServiceName(ctx)
+			methodName, _ := twirp.MethodName(ctx)
+
+			if c.spanFilter != nil && !c.spanFilter(ctx, packageName, serviceName, methodName) {
+				return next(ctx, req)
+			}
+
 			tracer := c.getTracerProvider(ctx).Tracer(InstrumentationName)
 
-			fullMethod, attributes := commonAtrributes(ctx)
+			fullMethod, attributes := commonAtrributes(packageName, serviceName, methodName)
+
+			if c.spanNameFormatter != nil {
+				fullMethod = c.spanNameFormatter(ctx, packageName, serviceName, methodName)
+			}
+
+			if len(c.staticAttributes) > 0 {
+				attributes = append(attributes, c.staticAttributes...)
+			}
 
 			ctx, span := tracer.Start(
 				ctx,
@@ -105,6 +132,14 @@ func interceptor(kind trace.SpanKind, options []Option) twirp.Interceptor {
 
 			defer span.End()
 
+			if kind == trace.SpanKindClient {
+				if injected, err := injectPropagationHeaders(ctx, c); err == nil {
+					ctx = injected
+				}
+
+				ctx = prepareClientHeaderCapture(ctx, c)
+			}
+
 			resp, err := next(ctx, req)
 			if err != nil {
 				span.SetStatus(codes.Error, err.Error())
@@ -112,16 +147,16 @@ func interceptor(kind trace.SpanKind, options []Option) twirp.Interceptor {
 
 			span.SetAttributes(getTwirpErrorAttributes(err)...)
 
+			if capture := capturedHeadersFromContext(ctx); capture != nil {
+				span.SetAttributes(capture.attributes()...)
+			}
+
 			return resp, err
 		}
 	}
 }
 
-func commonAtrributes(ctx context.Context) (string, []attribute.KeyValue) {
-	packageName, _ := twirp.PackageName(ctx)
-	serviceName, _ := twirp.ServiceName(ctx)
-	methodName, _ := twirp.MethodName(ctx)
-
+func commonAtrributes(packageName, serviceName, methodName string) (string, []attribute.KeyValue) {
 	fullMethod := packageName + "." + serviceName + "/" + methodName
 
 	return fullMethod, []attribute.KeyValue{